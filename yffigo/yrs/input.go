@@ -0,0 +1,437 @@
+package yrs
+
+/*
+#include "./include/libyrs.h"
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"runtime"
+	"unsafe"
+)
+
+// yInputContentSize is large enough to hold any variant of the C union
+// backing a YInput's value (the widest variant, a JSON map, stores two
+// pointers: a `char **keys` and a `struct YInput *values`).
+const yInputContentSize = 16
+
+// YInput represents a data structure used to pass input values into a
+// shared document. Its value is held in a pinned Go allocation shaped like
+// the real C union, rather than the struct's own memory, so that nested
+// and variable-sized variants (arrays, maps, JSON) fit uniformly alongside
+// the original scalar cases.
+type YInput struct {
+	Tag uint32
+	Len uint32
+
+	mem      []byte
+	pinner   runtime.Pinner
+	cAllocs  []unsafe.Pointer // C heap allocations owned by this input
+	children []*YInput        // child inputs kept alive until Free
+}
+
+// YInput types
+const (
+	Y_JSON_BOOL  = C.Y_JSON_BOOL
+	Y_JSON_NUM   = C.Y_JSON_NUM
+	Y_JSON_INT   = C.Y_JSON_INT
+	Y_JSON_STR   = C.Y_JSON_STR
+	Y_JSON_BUF   = C.Y_JSON_BUF
+	Y_JSON_ARR   = C.Y_JSON_ARR
+	Y_JSON_MAP   = C.Y_JSON_MAP
+	Y_JSON_NULL  = C.Y_JSON_NULL
+	Y_JSON_UNDEF = C.Y_JSON_UNDEF
+	Y_ARRAY      = C.Y_ARRAY
+	Y_MAP        = C.Y_MAP
+	Y_TEXT       = C.Y_TEXT
+	Y_XML_ELEM   = C.Y_XML_ELEM
+	Y_XML_TEXT   = C.Y_XML_TEXT
+	Y_XML_FRAG   = C.Y_XML_FRAG
+	Y_DOC        = C.Y_DOC
+	Y_WEAK_LINK  = C.Y_WEAK_LINK
+	Y_UNDEFINED  = C.Y_UNDEFINED
+	Y_TRUE       = C.Y_TRUE
+	Y_FALSE      = C.Y_FALSE
+
+	Y_OFFSET_BYTES = C.Y_OFFSET_BYTES
+	Y_OFFSET_UTF16 = C.Y_OFFSET_UTF16
+)
+
+// newInput allocates the pinned content buffer for a YInput and lets write
+// fill it in before the C call that consumes it.
+func newInput(tag uint32, length uint32, write func(mem []byte)) *YInput {
+	mem := make([]byte, yInputContentSize)
+	in := &YInput{Tag: tag, Len: length, mem: mem}
+	in.pinner.Pin(&mem[0])
+	if write != nil {
+		write(mem)
+	}
+	return in
+}
+
+// NewYInputBool creates a YInput for a boolean value.
+func NewYInputBool(value bool) *YInput {
+	flag := C.int8_t(Y_FALSE)
+	if value {
+		flag = C.int8_t(Y_TRUE)
+	}
+	return newInput(Y_JSON_BOOL, 1, func(mem []byte) {
+		*(*C.int8_t)(unsafe.Pointer(&mem[0])) = flag
+	})
+}
+
+// NewYInputInt creates a YInput for an integer value.
+func NewYInputInt(value int64) *YInput {
+	return newInput(Y_JSON_INT, 1, func(mem []byte) {
+		*(*C.int64_t)(unsafe.Pointer(&mem[0])) = C.int64_t(value)
+	})
+}
+
+// NewYInputFloat creates a YInput for a floating-point value.
+func NewYInputFloat(value float64) *YInput {
+	return newInput(Y_JSON_NUM, 1, func(mem []byte) {
+		*(*C.double)(unsafe.Pointer(&mem[0])) = C.double(value)
+	})
+}
+
+// NewYInputString creates a YInput for a string value.
+func NewYInputString(value string) *YInput {
+	cstr := C.CString(value)
+	in := newInput(Y_JSON_STR, 1, func(mem []byte) {
+		*(**C.char)(unsafe.Pointer(&mem[0])) = cstr
+	})
+	in.cAllocs = append(in.cAllocs, unsafe.Pointer(cstr))
+	return in
+}
+
+// NewYInputBinary creates a YInput for a raw byte buffer.
+func NewYInputBinary(value []byte) *YInput {
+	var ptr unsafe.Pointer
+	if len(value) > 0 {
+		ptr = C.CBytes(value)
+	}
+	in := newInput(Y_JSON_BUF, uint32(len(value)), func(mem []byte) {
+		*(*unsafe.Pointer)(unsafe.Pointer(&mem[0])) = ptr
+	})
+	if ptr != nil {
+		in.cAllocs = append(in.cAllocs, ptr)
+	}
+	return in
+}
+
+// NewYInputNull creates a YInput representing a JSON null value.
+func NewYInputNull() *YInput {
+	return newInput(Y_JSON_NULL, 0, nil)
+}
+
+// toYInput converts a Go value produced by a JSON-like literal (bool,
+// int/int64, float64, string, []byte, nil, []any or map[string]any) into a
+// YInput, recursing into nested arrays and maps.
+func toYInput(value any) *YInput {
+	switch v := value.(type) {
+	case nil:
+		return NewYInputNull()
+	case bool:
+		return NewYInputBool(v)
+	case int:
+		return NewYInputInt(int64(v))
+	case int64:
+		return NewYInputInt(v)
+	case float64:
+		return NewYInputFloat(v)
+	case string:
+		return NewYInputString(v)
+	case []byte:
+		return NewYInputBinary(v)
+	case []any:
+		items := make([]*YInput, len(v))
+		for i, e := range v {
+			items[i] = toYInput(e)
+		}
+		return NewYInputArray(items)
+	case map[string]any:
+		return NewYInputMap(v)
+	default:
+		return NewYInputNull()
+	}
+}
+
+// NewYInputArray creates a YInput for a JSON array built from the given
+// items. Ownership of items is transferred to the returned YInput; they are
+// freed when it is.
+func NewYInputArray(items []*YInput) *YInput {
+	n := len(items)
+	var valuesPtr *C.struct_YInput
+	if n > 0 {
+		valuesPtr = (*C.struct_YInput)(C.malloc(C.size_t(n) * C.size_t(unsafe.Sizeof(C.struct_YInput{}))))
+		slice := unsafe.Slice(valuesPtr, n)
+		for i, item := range items {
+			slice[i] = item.toC()
+		}
+	}
+	in := newInput(Y_JSON_ARR, uint32(n), func(mem []byte) {
+		*(**C.struct_YInput)(unsafe.Pointer(&mem[0])) = valuesPtr
+	})
+	in.children = items
+	if valuesPtr != nil {
+		in.cAllocs = append(in.cAllocs, unsafe.Pointer(valuesPtr))
+	}
+	return in
+}
+
+// NewYInputMap creates a YInput for a JSON map built from the given values,
+// converting each entry through toYInput. Keys are not sorted: they follow
+// Go's map iteration order, which is unspecified; callers that need stable
+// ordering should encode it into the keys themselves.
+func NewYInputMap(values map[string]any) *YInput {
+	n := len(values)
+	keys := make([]*C.char, 0, n)
+	children := make([]*YInput, 0, n)
+	for k, v := range values {
+		keys = append(keys, C.CString(k))
+		children = append(children, toYInput(v))
+	}
+
+	var keysPtr **C.char
+	var valuesPtr *C.struct_YInput
+	if n > 0 {
+		keysPtr = (**C.char)(C.malloc(C.size_t(n) * C.size_t(unsafe.Sizeof((*C.char)(nil)))))
+		keysSlice := unsafe.Slice(keysPtr, n)
+		valuesPtr = (*C.struct_YInput)(C.malloc(C.size_t(n) * C.size_t(unsafe.Sizeof(C.struct_YInput{}))))
+		valuesSlice := unsafe.Slice(valuesPtr, n)
+		for i := range keys {
+			keysSlice[i] = keys[i]
+			valuesSlice[i] = children[i].toC()
+		}
+	}
+
+	in := newInput(Y_JSON_MAP, uint32(n), func(mem []byte) {
+		*(**C.char)(unsafe.Pointer(&mem[0])) = keysPtr
+		*(**C.struct_YInput)(unsafe.Pointer(&mem[8])) = valuesPtr
+	})
+	in.children = children
+	for _, k := range keys {
+		in.cAllocs = append(in.cAllocs, unsafe.Pointer(k))
+	}
+	if keysPtr != nil {
+		in.cAllocs = append(in.cAllocs, unsafe.Pointer(keysPtr))
+	}
+	if valuesPtr != nil {
+		in.cAllocs = append(in.cAllocs, unsafe.Pointer(valuesPtr))
+	}
+	return in
+}
+
+// NewYInputJSON creates a YInput by parsing a raw JSON document into nested
+// YInput arrays/maps/scalars.
+func NewYInputJSON(raw string) *YInput {
+	var decoded any
+	if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+		return NewYInputNull()
+	}
+	return toYInput(decoded)
+}
+
+// branchRefInput builds a YInput that references an existing shared branch
+// (YArray, YMap, YText or one of the XML types) rather than creating a new
+// one, as used when moving a shared type into another collection.
+func branchRefInput(tag uint32, branch *Branch) *YInput {
+	return newInput(tag, 0, func(mem []byte) {
+		*(**C.Branch)(unsafe.Pointer(&mem[0])) = branch.ptr
+	})
+}
+
+// NewYInputYArray creates a YInput wrapping a reference to an existing
+// YArray branch.
+func NewYInputYArray(branch *Branch) *YInput { return branchRefInput(Y_ARRAY, branch) }
+
+// NewYInputYMap creates a YInput wrapping a reference to an existing YMap
+// branch.
+func NewYInputYMap(branch *Branch) *YInput { return branchRefInput(Y_MAP, branch) }
+
+// NewYInputYText creates a YInput wrapping a reference to an existing
+// YText branch.
+func NewYInputYText(branch *Branch) *YInput { return branchRefInput(Y_TEXT, branch) }
+
+// NewYInputYXmlElement creates a YInput wrapping a reference to an existing
+// YXmlElement branch.
+func NewYInputYXmlElement(branch *Branch) *YInput { return branchRefInput(Y_XML_ELEM, branch) }
+
+// NewYInputYXmlText creates a YInput wrapping a reference to an existing
+// YXmlText branch.
+func NewYInputYXmlText(branch *Branch) *YInput { return branchRefInput(Y_XML_TEXT, branch) }
+
+// NewYInputYDoc creates a YInput embedding child as a subdocument value.
+func NewYInputYDoc(child *YDoc) *YInput {
+	return newInput(Y_DOC, 0, func(mem []byte) {
+		*(**C.YDoc)(unsafe.Pointer(&mem[0])) = child.ptr
+	})
+}
+
+// Free releases every resource owned by this YInput, recursing into any
+// child inputs (array elements, map values).
+func (input *YInput) Free() {
+	for _, child := range input.children {
+		child.Free()
+	}
+	for _, ptr := range input.cAllocs {
+		C.free(ptr)
+	}
+	if len(input.mem) > 0 {
+		input.pinner.Unpin()
+	}
+}
+
+// toC converts a YInput to C.struct_YInput.
+func (input *YInput) toC() C.struct_YInput {
+	var value C.union_YInputContent
+	if len(input.mem) > 0 {
+		value = *(*C.union_YInputContent)(unsafe.Pointer(&input.mem[0]))
+	}
+	return C.struct_YInput{
+		tag:   C.int8_t(input.Tag),
+		len:   C.uint32_t(input.Len),
+		value: value,
+	}
+}
+
+// YOutput represents the output from Yrs API methods. It stores the raw C
+// struct verbatim so that accessor helpers (GetValueAsArray, GetValueAsMap,
+// GetValueAsYDoc, ...) can hand it back to C-side walker functions without
+// having to reconstruct it from copied fields.
+type YOutput struct {
+	raw C.struct_YOutput
+}
+
+// newYOutput wraps a YOutput value returned by value from a C function.
+func newYOutput(raw C.struct_YOutput) YOutput {
+	return YOutput{raw: raw}
+}
+
+// newYOutputPtr wraps a (possibly NULL) *C.struct_YOutput returned by a C
+// function, freeing nothing - callers that own the pointer must call
+// youtput_destroy themselves once done.
+func newYOutputPtr(raw *C.struct_YOutput) *YOutput {
+	if raw == nil {
+		return nil
+	}
+	out := newYOutput(*raw)
+	return &out
+}
+
+// GetValueAsString returns the value as a string if the YOutput is of type
+// string.
+func (output *YOutput) GetValueAsString() string {
+	if output.raw.tag != Y_JSON_STR {
+		return ""
+	}
+	strPtr := *(**C.char)(unsafe.Pointer(&output.raw.value))
+	return C.GoString(strPtr)
+}
+
+// GetValueAsInt returns the value as an integer if the YOutput is of type
+// int.
+func (output *YOutput) GetValueAsInt() int64 {
+	if output.raw.tag != Y_JSON_INT {
+		return 0
+	}
+	return int64(*(*C.int64_t)(unsafe.Pointer(&output.raw.value)))
+}
+
+// GetValueAsFloat returns the value as a float64 if the YOutput is of type
+// float.
+func (output *YOutput) GetValueAsFloat() float64 {
+	if output.raw.tag != Y_JSON_NUM {
+		return 0.0
+	}
+	return float64(*(*C.double)(unsafe.Pointer(&output.raw.value)))
+}
+
+// GetValueAsBool returns the value as a boolean if the YOutput is of type
+// bool.
+func (output *YOutput) GetValueAsBool() bool {
+	if output.raw.tag != Y_JSON_BOOL {
+		return false
+	}
+	flag := *(*C.int8_t)(unsafe.Pointer(&output.raw.value))
+	return flag == Y_TRUE
+}
+
+// GetValueAsBytes returns the value as a byte slice if the YOutput is of
+// type buffer.
+func (output *YOutput) GetValueAsBytes() []byte {
+	if output.raw.tag != Y_JSON_BUF {
+		return nil
+	}
+	bufPtr := *(*unsafe.Pointer)(unsafe.Pointer(&output.raw.value))
+	return C.GoBytes(bufPtr, C.int(output.raw.len))
+}
+
+// GetValueAsArray returns the value as a slice of YOutput if the YOutput
+// holds a JSON array or a nested YArray.
+func (output *YOutput) GetValueAsArray() []YOutput {
+	if output.raw.tag != Y_JSON_ARR && output.raw.tag != Y_ARRAY {
+		return nil
+	}
+	n := int(C.youtput_array_len(&output.raw))
+	if n == 0 {
+		return nil
+	}
+	result := make([]YOutput, n)
+	for i := 0; i < n; i++ {
+		item := C.youtput_array_get(&output.raw, C.uint32_t(i))
+		result[i] = newYOutput(*item)
+	}
+	return result
+}
+
+// GetValueAsMap returns the value as a string-keyed map of YOutput if the
+// YOutput holds a JSON map or a nested YMap.
+func (output *YOutput) GetValueAsMap() map[string]YOutput {
+	if output.raw.tag != Y_JSON_MAP && output.raw.tag != Y_MAP {
+		return nil
+	}
+	n := int(C.youtput_map_len(&output.raw))
+	if n == 0 {
+		return nil
+	}
+	result := make(map[string]YOutput, n)
+	iter := C.youtput_map_iter(&output.raw)
+	defer C.youtput_map_iter_destroy(iter)
+	for entry := C.youtput_map_iter_next(iter); entry != nil; entry = C.youtput_map_iter_next(iter) {
+		result[C.GoString(entry.key)] = newYOutput(entry.value)
+	}
+	return result
+}
+
+// GetValueAsYDoc returns the embedded subdocument if the YOutput holds one,
+// or nil otherwise.
+func (output *YOutput) GetValueAsYDoc() *YDoc {
+	if output.raw.tag != Y_DOC {
+		return nil
+	}
+	doc := C.youtput_read_ydoc(&output.raw)
+	if doc == nil {
+		return nil
+	}
+	return &YDoc{ptr: doc}
+}
+
+// GetValueAsYArray returns the referenced YArray branch if the YOutput
+// holds a nested shared array, or nil otherwise.
+func (output *YOutput) GetValueAsYArray() *Branch {
+	if output.raw.tag != Y_ARRAY {
+		return nil
+	}
+	branch := *(**C.Branch)(unsafe.Pointer(&output.raw.value))
+	if branch == nil {
+		return nil
+	}
+	return &Branch{ptr: branch}
+}
+
+// GetValueType returns the type of the value stored in YOutput.
+func (output *YOutput) GetValueType() int8 {
+	return int8(output.raw.tag)
+}
@@ -0,0 +1,423 @@
+package yrs
+
+/*
+#include "./include/libyrs.h"
+#include <stdlib.h>
+
+extern void goMapObserveTrampoline(YMapEvent *event, void *state);
+extern void goArrayObserveTrampoline(YArrayEvent *event, void *state);
+extern void goTextObserveTrampoline(YTextEvent *event, void *state);
+extern void goUpdateV1Trampoline(YTransaction *txn, const uint8_t *update, uint32_t update_len, void *origin, uint32_t origin_len, void *state);
+extern void goAfterTransactionTrampoline(YTransaction *txn, void *state);
+
+static YSubscription *yrs_ymap_observe(Branch *branch, void *state) {
+	return ymap_observe(branch, state, goMapObserveTrampoline);
+}
+
+static YSubscription *yrs_yarray_observe(Branch *branch, void *state) {
+	return yarray_observe(branch, state, goArrayObserveTrampoline);
+}
+
+static YSubscription *yrs_ytext_observe(Branch *branch, void *state) {
+	return ytext_observe(branch, state, goTextObserveTrampoline);
+}
+
+static YSubscription *yrs_ydoc_observe_updates_v1(YDoc *doc, void *state) {
+	return ydoc_observe_updates_v1(doc, state, goUpdateV1Trampoline);
+}
+
+static YSubscription *yrs_ydoc_observe_after_transaction(YDoc *doc, void *state) {
+	return ydoc_observe_after_transaction(doc, state, goAfterTransactionTrampoline);
+}
+*/
+import "C"
+import (
+	"runtime/cgo"
+	"sync"
+	"unsafe"
+)
+
+// Subscription represents a live registration of a Go callback against a
+// yffi observer. Close it when the callback is no longer needed, or the
+// underlying cgo.Handle will leak.
+type Subscription struct {
+	ptr   *C.YSubscription
+	entry *callbackEntry
+}
+
+// Close cancels the subscription and releases the cgo.Handle backing it.
+// Close synchronizes with any callback delivery already in flight via the
+// entry's own mutex, so a trampoline can never observe a handle torn down
+// mid-call.
+func (s *Subscription) Close() {
+	if s.ptr == nil {
+		return
+	}
+	s.entry.mu.Lock()
+	s.entry.closed = true
+	s.entry.mu.Unlock()
+
+	C.yunobserve(s.ptr)
+	releaseHandle(unsafe.Pointer(s.entry.handle))
+	s.entry.handle.Delete()
+	s.ptr = nil
+}
+
+// callbackEntry pairs a cgo.Handle with a mutex and closed flag, so that
+// Close (which may run on a different goroutine than the one delivering
+// callbacks) can never race a trampoline's handle lookup: the lookup reads
+// closed and the handle under mu, and Close sets closed under mu before it
+// deletes the handle.
+type callbackEntry struct {
+	mu     sync.Mutex
+	handle cgo.Handle
+	closed bool
+}
+
+// callValue returns the callback stored in this entry, or ok=false if the
+// subscription has already been closed.
+func (e *callbackEntry) callValue() (value any, ok bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return nil, false
+	}
+	return e.handle.Value(), true
+}
+
+// handles keeps every live callback entry reachable by the address of the
+// cgo.Handle passed as the state pointer to yffi, so that the trampolines
+// below can look them up without racing against concurrent commits on other
+// documents.
+var handles sync.Map // map[unsafe.Pointer]*callbackEntry
+
+func registerHandle(value any) (*callbackEntry, unsafe.Pointer) {
+	h := cgo.NewHandle(value)
+	ptr := unsafe.Pointer(h)
+	entry := &callbackEntry{handle: h}
+	handles.Store(ptr, entry)
+	return entry, ptr
+}
+
+func releaseHandle(ptr unsafe.Pointer) {
+	handles.Delete(ptr)
+}
+
+// loadCallback resolves the state pointer handed to a trampoline back to
+// its Go callback, or ok=false if the subscription was never registered or
+// has since been closed.
+func loadCallback(state unsafe.Pointer) (value any, ok bool) {
+	v, found := handles.Load(state)
+	if !found {
+		return nil, false
+	}
+	return v.(*callbackEntry).callValue()
+}
+
+// EntryChange describes how a single YMap key changed within an observed
+// transaction.
+type EntryChange struct {
+	Key      string
+	Action   string // "insert", "update" or "delete"
+	OldValue *YOutput
+	NewValue *YOutput
+}
+
+// DeltaOp identifies what a single Delta entry represents within an
+// observed YArray or YText change batch.
+type DeltaOp int
+
+const (
+	DeltaRetain DeltaOp = iota
+	DeltaInsert
+	DeltaDelete
+)
+
+// Delta describes one retain/insert/delete op from the delta format shared
+// by YArrayEvent and YTextEvent, mirroring lib0's delta encoding: Retain and
+// Delete carry a length, Insert carries the inserted values, and any op may
+// carry the formatting attributes active at that point.
+type Delta struct {
+	Op         DeltaOp
+	Len        uint32 // valid for DeltaRetain and DeltaDelete
+	Insert     []YOutput
+	Attributes map[string]*YOutput
+}
+
+// PathSegment identifies one step of the path from a document's root down
+// to the branch that raised an event: either a YMap key or a YArray/YText
+// index.
+type PathSegment struct {
+	IsKey bool
+	Key   string // valid when IsKey is true
+	Index uint32 // valid when IsKey is false
+}
+
+// YMapEvent describes a change batch observed on a YMap branch.
+type YMapEvent struct {
+	Target *Branch
+	Origin []byte
+	Path   []PathSegment
+	Keys   []EntryChange
+}
+
+// YArrayEvent describes a change batch observed on a YArray branch.
+type YArrayEvent struct {
+	Target *Branch
+	Origin []byte
+	Path   []PathSegment
+	Delta  []Delta
+}
+
+// YTextEvent describes a change batch observed on a YText branch.
+type YTextEvent struct {
+	Target *Branch
+	Origin []byte
+	Path   []PathSegment
+	Delta  []Delta
+}
+
+//export goMapObserveTrampoline
+func goMapObserveTrampoline(event *C.YMapEvent, state unsafe.Pointer) {
+	value, ok := loadCallback(state)
+	if !ok {
+		return
+	}
+	fn, ok := value.(func(*YMapEvent))
+	if !ok {
+		return
+	}
+	fn(translateMapEvent(event))
+}
+
+//export goArrayObserveTrampoline
+func goArrayObserveTrampoline(event *C.YArrayEvent, state unsafe.Pointer) {
+	value, ok := loadCallback(state)
+	if !ok {
+		return
+	}
+	fn, ok := value.(func(*YArrayEvent))
+	if !ok {
+		return
+	}
+	fn(translateArrayEvent(event))
+}
+
+//export goTextObserveTrampoline
+func goTextObserveTrampoline(event *C.YTextEvent, state unsafe.Pointer) {
+	value, ok := loadCallback(state)
+	if !ok {
+		return
+	}
+	fn, ok := value.(func(*YTextEvent))
+	if !ok {
+		return
+	}
+	fn(translateTextEvent(event))
+}
+
+//export goUpdateV1Trampoline
+func goUpdateV1Trampoline(txn *C.YTransaction, update *C.uint8_t, updateLen C.uint32_t, origin unsafe.Pointer, originLen C.uint32_t, state unsafe.Pointer) {
+	value, ok := loadCallback(state)
+	if !ok {
+		return
+	}
+	fn, ok := value.(func([]byte, []byte))
+	if !ok {
+		return
+	}
+	updateBytes := C.GoBytes(unsafe.Pointer(update), C.int(updateLen))
+	originBytes := C.GoBytes(origin, C.int(originLen))
+	fn(updateBytes, originBytes)
+}
+
+//export goAfterTransactionTrampoline
+func goAfterTransactionTrampoline(txn *C.YTransaction, state unsafe.Pointer) {
+	value, ok := loadCallback(state)
+	if !ok {
+		return
+	}
+	fn, ok := value.(func(*YTransaction))
+	if !ok {
+		return
+	}
+	fn(&YTransaction{ptr: txn})
+}
+
+// translatePath resolves the path from the document root down to the
+// branch that raised event, shared by all three event translators below
+// since yevent_path accepts any of the YMapEvent/YArrayEvent/YTextEvent
+// pointers through its opaque event parameter.
+func translatePath(event unsafe.Pointer) []PathSegment {
+	var length C.uint32_t
+	segs := C.yevent_path(event, &length)
+	if segs == nil {
+		return nil
+	}
+	defer C.yevent_path_destroy(segs, length)
+	n := int(length)
+	slice := unsafe.Slice(segs, n)
+	path := make([]PathSegment, n)
+	for i, s := range slice {
+		if s.tag == C.Y_EVENT_PATH_KEY {
+			path[i] = PathSegment{IsKey: true, Key: C.GoString(*(**C.char)(unsafe.Pointer(&s.value)))}
+		} else {
+			path[i] = PathSegment{Index: uint32(*(*C.uint32_t)(unsafe.Pointer(&s.value)))}
+		}
+	}
+	return path
+}
+
+// translateDelta converts a yffi YEventChange array (as returned by
+// yarray_event_delta/ytext_event_delta) into the tagged Delta ops it
+// represents, preserving the retain/insert/delete distinction the raw
+// YOutput values alone would lose.
+func translateDelta(raw *C.struct_YEventChange, n int) []Delta {
+	if raw == nil || n == 0 {
+		return nil
+	}
+	slice := unsafe.Slice(raw, n)
+	deltas := make([]Delta, n)
+	for i, c := range slice {
+		d := Delta{Len: uint32(c.len)}
+		switch c.tag {
+		case C.Y_EVENT_CHANGE_ADD:
+			d.Op = DeltaInsert
+			if c.values != nil {
+				values := unsafe.Slice(c.values, int(c.len))
+				d.Insert = make([]YOutput, len(values))
+				for j, v := range values {
+					d.Insert[j] = newYOutput(v)
+				}
+			}
+		case C.Y_EVENT_CHANGE_DELETE:
+			d.Op = DeltaDelete
+		default:
+			d.Op = DeltaRetain
+		}
+		if c.attributes != nil && c.attributes_len > 0 {
+			attrs := unsafe.Slice(c.attributes, int(c.attributes_len))
+			d.Attributes = make(map[string]*YOutput, len(attrs))
+			for _, a := range attrs {
+				out := newYOutput(a.value)
+				d.Attributes[C.GoString(a.key)] = &out
+			}
+		}
+		deltas[i] = d
+	}
+	return deltas
+}
+
+func translateMapEvent(event *C.YMapEvent) *YMapEvent {
+	branch := C.ymap_event_target(event)
+	txn := C.ymap_event_transaction(event)
+	var originLen C.uint32_t
+	origin := C.ytransaction_origin(txn, &originLen)
+	out := &YMapEvent{
+		Target: &Branch{ptr: branch},
+		Origin: C.GoBytes(origin, C.int(originLen)),
+		Path:   translatePath(unsafe.Pointer(event)),
+	}
+
+	var keysLen C.uint32_t
+	changes := C.ymap_event_keys(event, &keysLen)
+	defer C.ymap_event_keys_destroy(changes, keysLen)
+	n := int(keysLen)
+	slice := unsafe.Slice(changes, n)
+	for _, change := range slice {
+		action := "update"
+		switch change.tag {
+		case C.Y_EVENT_KEY_CHANGE_ADD:
+			action = "insert"
+		case C.Y_EVENT_KEY_CHANGE_DELETE:
+			action = "delete"
+		}
+		ec := EntryChange{Key: C.GoString(change.key), Action: action}
+		if change.old_value != nil {
+			ec.OldValue = newYOutputPtr(change.old_value)
+		}
+		if change.new_value != nil {
+			ec.NewValue = newYOutputPtr(change.new_value)
+		}
+		out.Keys = append(out.Keys, ec)
+	}
+	return out
+}
+
+func translateArrayEvent(event *C.YArrayEvent) *YArrayEvent {
+	branch := C.yarray_event_target(event)
+	txn := C.yarray_event_transaction(event)
+	var originLen C.uint32_t
+	origin := C.ytransaction_origin(txn, &originLen)
+	out := &YArrayEvent{
+		Target: &Branch{ptr: branch},
+		Origin: C.GoBytes(origin, C.int(originLen)),
+		Path:   translatePath(unsafe.Pointer(event)),
+	}
+
+	var deltaLen C.uint32_t
+	delta := C.yarray_event_delta(event, &deltaLen)
+	defer C.yevent_delta_destroy(delta, deltaLen)
+	out.Delta = translateDelta(delta, int(deltaLen))
+	return out
+}
+
+func translateTextEvent(event *C.YTextEvent) *YTextEvent {
+	branch := C.ytext_event_target(event)
+	txn := C.ytext_event_transaction(event)
+	var originLen C.uint32_t
+	origin := C.ytransaction_origin(txn, &originLen)
+	out := &YTextEvent{
+		Target: &Branch{ptr: branch},
+		Origin: C.GoBytes(origin, C.int(originLen)),
+		Path:   translatePath(unsafe.Pointer(event)),
+	}
+
+	var deltaLen C.uint32_t
+	delta := C.ytext_event_delta(event, &deltaLen)
+	defer C.yevent_delta_destroy(delta, deltaLen)
+	out.Delta = translateDelta(delta, int(deltaLen))
+	return out
+}
+
+// ObserveMap registers a callback invoked whenever this YMap branch changes.
+// Call Close on the returned Subscription to stop receiving events.
+func (b *Branch) ObserveMap(cb func(e *YMapEvent)) *Subscription {
+	entry, ptr := registerHandle(cb)
+	sub := C.yrs_ymap_observe(b.ptr, ptr)
+	return &Subscription{ptr: sub, entry: entry}
+}
+
+// ObserveArray registers a callback invoked whenever this YArray branch
+// changes.
+func (b *Branch) ObserveArray(cb func(e *YArrayEvent)) *Subscription {
+	entry, ptr := registerHandle(cb)
+	sub := C.yrs_yarray_observe(b.ptr, ptr)
+	return &Subscription{ptr: sub, entry: entry}
+}
+
+// ObserveText registers a callback invoked whenever this YText branch
+// changes.
+func (b *Branch) ObserveText(cb func(e *YTextEvent)) *Subscription {
+	entry, ptr := registerHandle(cb)
+	sub := C.yrs_ytext_observe(b.ptr, ptr)
+	return &Subscription{ptr: sub, entry: entry}
+}
+
+// ObserveUpdatesV1 registers a callback invoked with the lib0 v1-encoded
+// update and transaction origin every time a transaction commits against
+// this document.
+func (d *YDoc) ObserveUpdatesV1(cb func(update []byte, origin []byte)) *Subscription {
+	entry, ptr := registerHandle(cb)
+	sub := C.yrs_ydoc_observe_updates_v1(d.ptr, ptr)
+	return &Subscription{ptr: sub, entry: entry}
+}
+
+// ObserveAfterTransaction registers a callback invoked after every
+// transaction committed against this document, once all of its nested
+// side effects (including subdocument loading) have settled.
+func (d *YDoc) ObserveAfterTransaction(cb func(txn *YTransaction)) *Subscription {
+	entry, ptr := registerHandle(cb)
+	sub := C.yrs_ydoc_observe_after_transaction(d.ptr, ptr)
+	return &Subscription{ptr: sub, entry: entry}
+}
@@ -0,0 +1,72 @@
+package yrs
+
+/*
+#include "./include/libyrs.h"
+#include <stdlib.h>
+*/
+import "C"
+import "unsafe"
+
+// Snapshot captures the state of a document at a single point in time,
+// letting applications later reconstruct what it looked like then (e.g. for
+// blame-style history views).
+type Snapshot struct {
+	ptr *C.YSnapshot
+}
+
+// Snapshot captures the current state of the document backing this
+// transaction.
+func (t *YTransaction) Snapshot() *Snapshot {
+	ptr := C.ytransaction_snapshot(t.ptr)
+	if ptr == nil {
+		return nil
+	}
+	return &Snapshot{ptr: ptr}
+}
+
+// Encode serializes this Snapshot into a compact binary form, which
+// DecodeSnapshot turns back into a Snapshot.
+func (s *Snapshot) Encode() []byte {
+	var length C.uint32_t
+	ptr := C.ysnapshot_encode(s.ptr, &length)
+	return bytesFromCBinary(ptr, length)
+}
+
+// DecodeSnapshot restores a Snapshot previously serialized with Encode.
+func DecodeSnapshot(encoded []byte) *Snapshot {
+	if len(encoded) == 0 {
+		return nil
+	}
+	ptr := C.ysnapshot_decode((*C.uchar)(unsafe.Pointer(&encoded[0])), C.uint32_t(len(encoded)))
+	if ptr == nil {
+		return nil
+	}
+	return &Snapshot{ptr: ptr}
+}
+
+// EncodeStateFromSnapshotV1 computes a lib0 v1-encoded update which, when
+// applied to an empty document, reconstructs the state of this transaction's
+// document as of snap.
+func (t *YTransaction) EncodeStateFromSnapshotV1(snap *Snapshot) []byte {
+	var length C.uint32_t
+	ptr := C.ytransaction_encode_state_from_snapshot_v1(t.ptr, snap.ptr, &length)
+	return bytesFromCBinary(ptr, length)
+}
+
+// EncodeStateFromSnapshotV2 is the v2-encoding counterpart of
+// EncodeStateFromSnapshotV1, producing a more compact representation for
+// documents with long edit histories.
+func (t *YTransaction) EncodeStateFromSnapshotV2(snap *Snapshot) []byte {
+	var length C.uint32_t
+	ptr := C.ytransaction_encode_state_from_snapshot_v2(t.ptr, snap.ptr, &length)
+	return bytesFromCBinary(ptr, length)
+}
+
+// Destroy releases the resources associated with this Snapshot.
+func (s *Snapshot) Destroy() {
+	if s.ptr == nil {
+		return
+	}
+	C.ysnapshot_destroy(s.ptr)
+	s.ptr = nil
+}
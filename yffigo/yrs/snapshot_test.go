@@ -0,0 +1,95 @@
+package yrs
+
+import "testing"
+
+// TestSnapshotStateReconstructionV1V2Agree asserts that reconstructing a
+// document's state as of a past Snapshot produces the same result whether
+// it goes through the v1 or the v2 update encoding.
+func TestSnapshotStateReconstructionV1V2Agree(t *testing.T) {
+	doc := NewYDoc()
+	defer doc.Destroy()
+
+	text := doc.GetYText("text")
+
+	txn := doc.WriteTransaction("")
+	text.YTextInsert(txn, 0, "hello", nil)
+	snap := txn.Snapshot()
+	text.YTextInsert(txn, 5, " world", nil)
+	txn.Commit()
+
+	if snap == nil {
+		t.Fatal("Snapshot() returned nil")
+	}
+	defer snap.Destroy()
+
+	readTxn := doc.ReadTransaction()
+	updateV1 := readTxn.EncodeStateFromSnapshotV1(snap)
+	updateV2 := readTxn.EncodeStateFromSnapshotV2(snap)
+
+	if len(updateV1) == 0 || len(updateV2) == 0 {
+		t.Fatal("expected non-empty updates from both encoders")
+	}
+
+	docV1 := NewYDoc()
+	defer docV1.Destroy()
+	applyTxn1 := docV1.WriteTransaction("")
+	if err := applyTxn1.ApplyV1(updateV1); err != nil {
+		t.Fatalf("ApplyV1 failed: %v", err)
+	}
+	applyTxn1.Commit()
+
+	docV2 := NewYDoc()
+	defer docV2.Destroy()
+	applyTxn2 := docV2.WriteTransaction("")
+	if err := applyTxn2.ApplyV2(updateV2); err != nil {
+		t.Fatalf("ApplyV2 failed: %v", err)
+	}
+	applyTxn2.Commit()
+
+	gotV1 := docV1.GetYText("text").YTextString(docV1.ReadTransaction())
+	gotV2 := docV2.GetYText("text").YTextString(docV2.ReadTransaction())
+
+	if gotV1 != "hello" {
+		t.Errorf("v1-reconstructed state = %q, want %q", gotV1, "hello")
+	}
+	if gotV1 != gotV2 {
+		t.Errorf("v1 and v2 encoders disagree: v1 = %q, v2 = %q", gotV1, gotV2)
+	}
+}
+
+// TestSnapshotEncodeDecodeRoundTrip asserts that a Snapshot survives an
+// Encode/DecodeSnapshot round trip unchanged, as judged by the update it
+// produces against the same transaction.
+func TestSnapshotEncodeDecodeRoundTrip(t *testing.T) {
+	doc := NewYDoc()
+	defer doc.Destroy()
+
+	text := doc.GetYText("text")
+
+	txn := doc.WriteTransaction("")
+	text.YTextInsert(txn, 0, "hello", nil)
+	snap := txn.Snapshot()
+	txn.Commit()
+	if snap == nil {
+		t.Fatal("Snapshot() returned nil")
+	}
+	defer snap.Destroy()
+
+	encoded := snap.Encode()
+	if len(encoded) == 0 {
+		t.Fatal("Encode() returned empty bytes")
+	}
+
+	decoded := DecodeSnapshot(encoded)
+	if decoded == nil {
+		t.Fatal("DecodeSnapshot() returned nil")
+	}
+	defer decoded.Destroy()
+
+	readTxn := doc.ReadTransaction()
+	want := readTxn.EncodeStateFromSnapshotV1(snap)
+	got := readTxn.EncodeStateFromSnapshotV1(decoded)
+	if string(want) != string(got) {
+		t.Errorf("decoded snapshot produced a different update: got %x, want %x", got, want)
+	}
+}
@@ -0,0 +1,77 @@
+package yrs
+
+/*
+#include "./include/libyrs.h"
+#include <stdlib.h>
+*/
+import "C"
+import "unsafe"
+
+// Association determines which side of an edit a StickyIndex sticks to when
+// content is inserted exactly at its position.
+type Association int8
+
+const (
+	// AssocBefore sticks to the character before the index.
+	AssocBefore Association = -1
+	// AssocAfter sticks to the character after the index.
+	AssocAfter Association = 0
+)
+
+// StickyIndex is a position within a shared type that survives concurrent
+// remote edits, suitable for representing cursors and selections shared
+// between peers.
+type StickyIndex struct {
+	ptr *C.YStickyIndex
+}
+
+// NewStickyIndexFromIndex creates a StickyIndex at the given offset within
+// branch, as observed in txn.
+func NewStickyIndexFromIndex(branch *Branch, txn *YTransaction, index uint32, assoc Association) *StickyIndex {
+	ptr := C.ysticky_index_from_index(branch.ptr, txn.ptr, C.uint32_t(index), C.int8_t(assoc))
+	if ptr == nil {
+		return nil
+	}
+	return &StickyIndex{ptr: ptr}
+}
+
+// Encode serializes this StickyIndex into a compact binary form that can be
+// sent to a remote peer and later restored via DecodeStickyIndex.
+func (s *StickyIndex) Encode() []byte {
+	var length C.uint32_t
+	ptr := C.ysticky_index_encode(s.ptr, &length)
+	return bytesFromCBinary(ptr, length)
+}
+
+// DecodeStickyIndex restores a StickyIndex previously serialized with
+// Encode.
+func DecodeStickyIndex(encoded []byte) *StickyIndex {
+	if len(encoded) == 0 {
+		return nil
+	}
+	ptr := C.ysticky_index_decode((*C.uchar)(unsafe.Pointer(&encoded[0])), C.uint32_t(len(encoded)))
+	if ptr == nil {
+		return nil
+	}
+	return &StickyIndex{ptr: ptr}
+}
+
+// Resolve computes this StickyIndex's live offset within its owning branch
+// as of txn. ok is false if the branch has since been deleted.
+func (s *StickyIndex) Resolve(txn *YTransaction) (branch *Branch, index uint32, ok bool) {
+	offset := C.ysticky_index_get_offset(s.ptr, txn.ptr)
+	if offset == nil {
+		return nil, 0, false
+	}
+	defer C.ysticky_index_offset_destroy(offset)
+	return &Branch{ptr: offset.branch}, uint32(offset.index), true
+}
+
+// Destroy releases the resources associated with this StickyIndex.
+func (s *StickyIndex) Destroy() {
+	if s.ptr == nil {
+		return
+	}
+	C.ysticky_index_destroy(s.ptr)
+	s.ptr = nil
+}
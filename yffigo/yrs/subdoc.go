@@ -0,0 +1,158 @@
+package yrs
+
+/*
+#include "./include/libyrs.h"
+#include <stdlib.h>
+
+extern void goSubdocsTrampoline(YSubdocsEvent *event, void *state);
+
+static YSubscription *yrs_ydoc_observe_subdocs(YDoc *doc, void *state) {
+	return ydoc_observe_subdocs(doc, state, goSubdocsTrampoline);
+}
+*/
+import "C"
+import (
+	"sync"
+	"unsafe"
+)
+
+// YDocOptions configures a YDoc created via NewYDocWithOptions.
+type YDocOptions struct {
+	// GUID uniquely identifies this document among its peers. Leave empty
+	// to have one generated.
+	GUID string
+	// CollectionID groups related documents (e.g. all docs belonging to
+	// the same workspace) for transport-layer routing.
+	CollectionID string
+	// AutoLoad causes this document to be loaded automatically as soon as
+	// it's integrated as a subdocument of an already-loaded parent.
+	AutoLoad bool
+	// ShouldLoad mirrors AutoLoad's effect for documents restored from a
+	// persisted parent, without requiring a round trip through the
+	// network layer.
+	ShouldLoad bool
+}
+
+// NewYDocWithOptions creates a new YDoc configured with the given options,
+// for use as a root document or as a subdocument embedded inside another
+// one via NewYInputYDoc.
+func NewYDocWithOptions(opts YDocOptions) *YDoc {
+	var cguid *C.char
+	if opts.GUID != "" {
+		cguid = C.CString(opts.GUID)
+		defer C.free(unsafe.Pointer(cguid))
+	}
+	var ccollection *C.char
+	if opts.CollectionID != "" {
+		ccollection = C.CString(opts.CollectionID)
+		defer C.free(unsafe.Pointer(ccollection))
+	}
+
+	cOpts := C.struct_YOptions{
+		guid:          cguid,
+		collection_id: ccollection,
+		auto_load:     C.uint8_t(boolToC(opts.AutoLoad)),
+		should_load:   C.uint8_t(boolToC(opts.ShouldLoad)),
+	}
+	doc := C.ydoc_new_with_options(cOpts)
+	return &YDoc{ptr: doc}
+}
+
+func boolToC(v bool) int {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// isSubdoc tracks whether a *C.YDoc is owned by a parent transaction, so
+// that Destroy doesn't free a document still referenced by its parent. A
+// pointer is marked exactly once, the first time it's seen via Subdocs or
+// ObserveSubdocs, and is never unmarked: subdocsOf/docsFromRefs can mint a
+// fresh *YDoc wrapper around the same underlying pointer on every call, and
+// the mark must still hold for every one of those wrappers' Destroy calls,
+// not just the first.
+var isSubdoc sync.Map // map[*C.YDoc]struct{}
+
+// Subdocs returns the subdocuments currently embedded (directly or
+// transitively) in the document backing this transaction.
+func (t *YTransaction) Subdocs() []*YDoc {
+	return subdocsOf(t)
+}
+
+func subdocsOf(t *YTransaction) []*YDoc {
+	var length C.uint32_t
+	cDocs := C.ydoc_subdocs(t.ptr, &length)
+	if cDocs == nil {
+		return nil
+	}
+	defer C.ydoc_subdocs_destroy(cDocs, length)
+
+	docs := docsFromRefs(cDocs, length)
+	for _, d := range docs {
+		isSubdoc.Store(d.ptr, struct{}{})
+	}
+	return docs
+}
+
+// Load requests that this subdocument's content be synced and made
+// available, as part of parentTxn. Has no effect on a root document.
+func (d *YDoc) Load(parentTxn *YTransaction) {
+	C.ydoc_load(d.ptr, parentTxn.ptr)
+}
+
+// Destroy releases the memory associated with a YDoc, unless it is a
+// subdocument still owned by a parent transaction - destroying those is the
+// parent document's responsibility.
+func (d *YDoc) Destroy() {
+	if d.ptr == nil {
+		return
+	}
+	if _, owned := isSubdoc.Load(d.ptr); owned {
+		d.ptr = nil
+		return
+	}
+	C.ydoc_destroy(d.ptr)
+	d.ptr = nil
+}
+
+func docsFromRefs(refs **C.YDoc, length C.uint32_t) []*YDoc {
+	if refs == nil || length == 0 {
+		return nil
+	}
+	slice := unsafe.Slice(refs, int(length))
+	docs := make([]*YDoc, len(slice))
+	for i, ptr := range slice {
+		docs[i] = &YDoc{ptr: ptr}
+	}
+	return docs
+}
+
+//export goSubdocsTrampoline
+func goSubdocsTrampoline(event *C.YSubdocsEvent, state unsafe.Pointer) {
+	value, ok := loadCallback(state)
+	if !ok {
+		return
+	}
+	fn, ok := value.(func(added, removed, loaded []*YDoc))
+	if !ok {
+		return
+	}
+	added := docsFromRefs(event.added, event.added_len)
+	removed := docsFromRefs(event.removed, event.removed_len)
+	loaded := docsFromRefs(event.loaded, event.loaded_len)
+	for _, docs := range [][]*YDoc{added, removed, loaded} {
+		for _, d := range docs {
+			isSubdoc.Store(d.ptr, struct{}{})
+		}
+	}
+	fn(added, removed, loaded)
+}
+
+// ObserveSubdocs registers a callback invoked whenever subdocuments of this
+// document are added, removed, or loaded.
+func (d *YDoc) ObserveSubdocs(cb func(added, removed, loaded []*YDoc)) *Subscription {
+	entry, ptr := registerHandle(cb)
+	sub := C.yrs_ydoc_observe_subdocs(d.ptr, ptr)
+	return &Subscription{ptr: sub, entry: entry}
+}
@@ -0,0 +1,163 @@
+package yrs
+
+/*
+#include "./include/libyrs.h"
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// bytesFromCBinary copies a C-owned byte buffer into a Go slice and frees the
+// original allocation via ybinary_destroy.
+func bytesFromCBinary(ptr *C.uint8_t, length C.uint32_t) []byte {
+	if ptr == nil {
+		return nil
+	}
+	defer C.ybinary_destroy(ptr, length)
+	return C.GoBytes(unsafe.Pointer(ptr), C.int(length))
+}
+
+// StateVectorV1 encodes the state vector of the document as seen from this
+// transaction, using lib0 v1 encoding. The result can be sent to a remote
+// peer so that it can compute a diff via StateDiffV1.
+func (t *YTransaction) StateVectorV1() []byte {
+	var length C.uint32_t
+	ptr := C.ytransaction_state_vector_v1(t.ptr, &length)
+	return bytesFromCBinary(ptr, length)
+}
+
+// StateDiffV1 computes an update, encoded with lib0 v1 encoding, containing
+// all changes unknown to the peer identified by stateVector.
+func (t *YTransaction) StateDiffV1(stateVector []byte) []byte {
+	var length C.uint32_t
+	var svPtr *C.uchar
+	if len(stateVector) > 0 {
+		svPtr = (*C.uchar)(unsafe.Pointer(&stateVector[0]))
+	}
+	ptr := C.ytransaction_state_diff_v1(t.ptr, svPtr, C.uint32_t(len(stateVector)), &length)
+	return bytesFromCBinary(ptr, length)
+}
+
+// StateVectorV2 is the v2-encoding counterpart of StateVectorV1.
+func (t *YTransaction) StateVectorV2() []byte {
+	var length C.uint32_t
+	ptr := C.ytransaction_state_vector_v2(t.ptr, &length)
+	return bytesFromCBinary(ptr, length)
+}
+
+// StateDiffV2 is the v2-encoding counterpart of StateDiffV1.
+func (t *YTransaction) StateDiffV2(stateVector []byte) []byte {
+	var length C.uint32_t
+	var svPtr *C.uchar
+	if len(stateVector) > 0 {
+		svPtr = (*C.uchar)(unsafe.Pointer(&stateVector[0]))
+	}
+	ptr := C.ytransaction_state_diff_v2(t.ptr, svPtr, C.uint32_t(len(stateVector)), &length)
+	return bytesFromCBinary(ptr, length)
+}
+
+// ApplyV1 applies a lib0 v1-encoded update, as produced by a remote peer's
+// StateDiffV1, to the document backing this transaction.
+func (t *YTransaction) ApplyV1(update []byte) error {
+	if len(update) == 0 {
+		return nil
+	}
+	code := C.ytransaction_apply(t.ptr, (*C.uchar)(unsafe.Pointer(&update[0])), C.uint32_t(len(update)))
+	if code != 0 {
+		return fmt.Errorf("yrs: failed to apply v1 update, error code %d", int(code))
+	}
+	return nil
+}
+
+// ApplyV2 applies a lib0 v2-encoded update to the document backing this
+// transaction.
+func (t *YTransaction) ApplyV2(update []byte) error {
+	if len(update) == 0 {
+		return nil
+	}
+	code := C.ytransaction_apply_v2(t.ptr, (*C.uchar)(unsafe.Pointer(&update[0])), C.uint32_t(len(update)))
+	if code != 0 {
+		return fmt.Errorf("yrs: failed to apply v2 update, error code %d", int(code))
+	}
+	return nil
+}
+
+// cBinarySlices copies a slice of byte slices into C-owned buffers and
+// returns the C arrays needed to pass them as `const uint8_t **` /
+// `const uint32_t *` pairs. Every element of the returned arrays, and the
+// arrays themselves, live on the C heap - never inside a Go-owned array -
+// since cgo's pointer checker rejects a Go pointer stored inside another Go
+// allocation crossing into C. The returned cleanup func frees all of it and
+// must be called once the C call returns.
+func cBinarySlices(updates [][]byte) (**C.uint8_t, *C.uint32_t, func()) {
+	n := len(updates)
+	if n == 0 {
+		return nil, nil, func() {}
+	}
+
+	ptrsHead := (**C.uint8_t)(C.malloc(C.size_t(n) * C.size_t(unsafe.Sizeof((*C.uint8_t)(nil)))))
+	lensHead := (*C.uint32_t)(C.malloc(C.size_t(n) * C.size_t(unsafe.Sizeof(C.uint32_t(0)))))
+	ptrs := unsafe.Slice(ptrsHead, n)
+	lens := unsafe.Slice(lensHead, n)
+	for i, u := range updates {
+		if len(u) > 0 {
+			ptrs[i] = (*C.uint8_t)(C.CBytes(u))
+		} else {
+			ptrs[i] = nil
+		}
+		lens[i] = C.uint32_t(len(u))
+	}
+
+	done := func() {
+		for _, p := range ptrs {
+			if p != nil {
+				C.free(unsafe.Pointer(p))
+			}
+		}
+		C.free(unsafe.Pointer(ptrsHead))
+		C.free(unsafe.Pointer(lensHead))
+	}
+	return ptrsHead, lensHead, done
+}
+
+// MergeUpdatesV1 merges a collection of lib0 v1-encoded updates into a
+// single one, discarding redundant information in the process.
+func MergeUpdatesV1(updates [][]byte) []byte {
+	if len(updates) == 0 {
+		return nil
+	}
+	ptrs, lens, done := cBinarySlices(updates)
+	defer done()
+	var length C.uint32_t
+	ptr := C.ymerge_updates_v1(ptrs, lens, C.uint32_t(len(updates)), &length)
+	return bytesFromCBinary(ptr, length)
+}
+
+// MergeUpdatesV2 is the v2-encoding counterpart of MergeUpdatesV1.
+func MergeUpdatesV2(updates [][]byte) []byte {
+	if len(updates) == 0 {
+		return nil
+	}
+	ptrs, lens, done := cBinarySlices(updates)
+	defer done()
+	var length C.uint32_t
+	ptr := C.ymerge_updates_v2(ptrs, lens, C.uint32_t(len(updates)), &length)
+	return bytesFromCBinary(ptr, length)
+}
+
+// UndoDiffV1 computes a lib0 v1-encoded update containing all changes
+// unknown to stateVector, in a form suitable for undoing a previously
+// applied update: deleted blocks are preserved as tombstones instead of
+// being garbage-collected.
+func (t *YTransaction) UndoDiffV1(stateVector []byte) []byte {
+	var length C.uint32_t
+	var svPtr *C.uchar
+	if len(stateVector) > 0 {
+		svPtr = (*C.uchar)(unsafe.Pointer(&stateVector[0]))
+	}
+	ptr := C.yundo_diff_v1(t.ptr, svPtr, C.uint32_t(len(stateVector)), &length)
+	return bytesFromCBinary(ptr, length)
+}
@@ -0,0 +1,102 @@
+package yrs
+
+/*
+#include "./include/libyrs.h"
+#include <stdlib.h>
+*/
+import "C"
+import "unsafe"
+
+// TextChunk represents a single run of a YText with uniform formatting, as
+// returned by Chunks. It mirrors the delta format consumed by editors such
+// as Quill or ProseMirror.
+type TextChunk struct {
+	Data       YOutput
+	Attributes map[string]*YOutput
+}
+
+// attrsToYInput converts a Go attribute map into the Y_JSON_MAP-tagged
+// YInput expected by ytext_insert_with_attributes,
+// ytext_insert_embed_with_attributes and ytext_format. A nil or empty map
+// yields a nil *YInput.
+func attrsToYInput(attrs map[string]any) *YInput {
+	if len(attrs) == 0 {
+		return nil
+	}
+	return NewYInputMap(attrs)
+}
+
+// YTextInsert inserts text into this YText branch at the given index,
+// optionally tagged with formatting attributes.
+func (b *Branch) YTextInsert(txn *YTransaction, index uint32, text string, attrs map[string]any) {
+	ctext := C.CString(text)
+	defer C.free(unsafe.Pointer(ctext))
+
+	if attrsInput := attrsToYInput(attrs); attrsInput != nil {
+		defer attrsInput.Free()
+		cAttrs := attrsInput.toC()
+		C.ytext_insert_with_attributes(b.ptr, txn.ptr, C.uint32_t(index), ctext, &cAttrs)
+		return
+	}
+	C.ytext_insert(b.ptr, txn.ptr, C.uint32_t(index), ctext)
+}
+
+// YTextInsertEmbed inserts a non-text embed (e.g. an image reference or a
+// nested shared type) into this YText branch at the given index.
+func (b *Branch) YTextInsertEmbed(txn *YTransaction, index uint32, content *YInput, attrs map[string]any) {
+	cContent := content.toC()
+
+	if attrsInput := attrsToYInput(attrs); attrsInput != nil {
+		defer attrsInput.Free()
+		cAttrs := attrsInput.toC()
+		C.ytext_insert_embed_with_attributes(b.ptr, txn.ptr, C.uint32_t(index), &cContent, &cAttrs)
+		return
+	}
+	C.ytext_insert_embed(b.ptr, txn.ptr, C.uint32_t(index), &cContent)
+}
+
+// YTextFormat applies formatting attributes to a range of this YText branch
+// without altering its content.
+func (b *Branch) YTextFormat(txn *YTransaction, index, length uint32, attrs map[string]any) {
+	attrsInput := attrsToYInput(attrs)
+	if attrsInput == nil {
+		return
+	}
+	defer attrsInput.Free()
+	cAttrs := attrsInput.toC()
+	C.ytext_format(b.ptr, txn.ptr, C.uint32_t(index), C.uint32_t(length), &cAttrs)
+}
+
+// YTextRemoveRange removes length UTF-16 code units (or bytes, depending on
+// the doc's offset kind) starting at index from this YText branch.
+func (b *Branch) YTextRemoveRange(txn *YTransaction, index, length uint32) {
+	C.ytext_remove_range(b.ptr, txn.ptr, C.uint32_t(index), C.uint32_t(length))
+}
+
+// YTextChunks returns the content of this YText branch as a sequence of
+// chunks, each carrying the formatting attributes active over that chunk -
+// the delta format expected by rich text editor bindings.
+func (b *Branch) YTextChunks(txn *YTransaction) []TextChunk {
+	var length C.uint32_t
+	cChunks := C.ytext_chunks(b.ptr, txn.ptr, &length)
+	if cChunks == nil {
+		return nil
+	}
+	defer C.ychunks_destroy(cChunks, length)
+
+	n := int(length)
+	slice := unsafe.Slice(cChunks, n)
+	chunks := make([]TextChunk, n)
+	for i, c := range slice {
+		chunks[i].Data = newYOutput(c.data)
+		if c.fmt != nil {
+			chunks[i].Attributes = make(map[string]*YOutput, int(c.fmt_len))
+			attrSlice := unsafe.Slice(c.fmt, int(c.fmt_len))
+			for _, a := range attrSlice {
+				out := newYOutput(a.value)
+				chunks[i].Attributes[C.GoString(a.key)] = &out
+			}
+		}
+	}
+	return chunks
+}
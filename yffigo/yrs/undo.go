@@ -0,0 +1,171 @@
+package yrs
+
+/*
+#include "./include/libyrs.h"
+#include <stdlib.h>
+
+extern void goUndoItemAddedTrampoline(YUndoEventData event, void *state);
+extern void goUndoItemPoppedTrampoline(YUndoEventData event, void *state);
+
+static YSubscription *yrs_yundo_manager_observe_added(YUndoManager *mgr, void *state) {
+	return yundo_manager_observe_added(mgr, state, goUndoItemAddedTrampoline);
+}
+
+static YSubscription *yrs_yundo_manager_observe_popped(YUndoManager *mgr, void *state) {
+	return yundo_manager_observe_popped(mgr, state, goUndoItemPoppedTrampoline);
+}
+*/
+import "C"
+import "unsafe"
+
+// UndoOptions configures a UndoManager.
+type UndoOptions struct {
+	// CaptureTimeoutMillis bounds how long consecutive edits are merged
+	// into a single undo step.
+	CaptureTimeoutMillis uint32
+	// TrackedOrigins restricts which transaction origins are recorded on
+	// the undo stack. An empty slice tracks every origin.
+	TrackedOrigins []string
+}
+
+// StackItem describes a single entry pushed onto (or popped from) an undo or
+// redo stack.
+type StackItem struct {
+	Kind   string // "undo" or "redo"
+	Origin []byte
+}
+
+// UndoManager batches and replays local edits, letting an application
+// implement undo/redo scoped to specific branches and transaction origins.
+type UndoManager struct {
+	ptr *C.YUndoManager
+}
+
+// NewUndoManager creates an UndoManager tracking edits made to doc.
+func NewUndoManager(doc *YDoc, opts UndoOptions) *UndoManager {
+	cOpts := C.struct_YUndoManagerOptions{
+		capture_timeout_millis: C.uint32_t(opts.CaptureTimeoutMillis),
+	}
+	mgr := C.yundo_manager_new(doc.ptr, &cOpts)
+	m := &UndoManager{ptr: mgr}
+	for _, origin := range opts.TrackedOrigins {
+		m.AddTrackedOrigin(origin)
+	}
+	return m
+}
+
+// AddScope registers branch as a root that this manager tracks changes for.
+func (m *UndoManager) AddScope(branch *Branch) {
+	C.yundo_manager_add_scope(m.ptr, branch.ptr)
+}
+
+// AddTrackedOrigin restricts tracking to transactions committed under the
+// given origin, in addition to any already tracked.
+func (m *UndoManager) AddTrackedOrigin(origin string) {
+	corigin := C.CString(origin)
+	defer C.free(unsafe.Pointer(corigin))
+	C.yundo_manager_add_origin(m.ptr, C.uint32_t(len(origin)), corigin)
+}
+
+// RemoveTrackedOrigin stops tracking transactions committed under the given
+// origin.
+func (m *UndoManager) RemoveTrackedOrigin(origin string) {
+	corigin := C.CString(origin)
+	defer C.free(unsafe.Pointer(corigin))
+	C.yundo_manager_remove_origin(m.ptr, C.uint32_t(len(origin)), corigin)
+}
+
+// Undo reverts the most recent undo step, returning false if the undo stack
+// was empty.
+func (m *UndoManager) Undo() bool {
+	return bool(C.yundo_manager_undo(m.ptr))
+}
+
+// Redo re-applies the most recently undone step, returning false if the
+// redo stack was empty.
+func (m *UndoManager) Redo() bool {
+	return bool(C.yundo_manager_redo(m.ptr))
+}
+
+// CanUndo reports whether the undo stack has at least one entry.
+func (m *UndoManager) CanUndo() bool {
+	return bool(C.yundo_manager_can_undo(m.ptr))
+}
+
+// CanRedo reports whether the redo stack has at least one entry.
+func (m *UndoManager) CanRedo() bool {
+	return bool(C.yundo_manager_can_redo(m.ptr))
+}
+
+// Clear empties both the undo and redo stacks.
+func (m *UndoManager) Clear() {
+	C.yundo_manager_clear(m.ptr)
+}
+
+// Stop resets the capture session, so that the next tracked edit starts a
+// new undo step instead of being merged into the previous one.
+func (m *UndoManager) Stop() {
+	C.yundo_manager_stop(m.ptr)
+}
+
+// Destroy releases the resources associated with this UndoManager.
+func (m *UndoManager) Destroy() {
+	if m.ptr == nil {
+		return
+	}
+	C.yundo_manager_destroy(m.ptr)
+	m.ptr = nil
+}
+
+//export goUndoItemAddedTrampoline
+func goUndoItemAddedTrampoline(event C.YUndoEventData, state unsafe.Pointer) {
+	value, ok := loadCallback(state)
+	if !ok {
+		return
+	}
+	fn, ok := value.(func(StackItem))
+	if !ok {
+		return
+	}
+	fn(translateUndoEvent(event))
+}
+
+//export goUndoItemPoppedTrampoline
+func goUndoItemPoppedTrampoline(event C.YUndoEventData, state unsafe.Pointer) {
+	value, ok := loadCallback(state)
+	if !ok {
+		return
+	}
+	fn, ok := value.(func(StackItem))
+	if !ok {
+		return
+	}
+	fn(translateUndoEvent(event))
+}
+
+func translateUndoEvent(event C.YUndoEventData) StackItem {
+	kind := "undo"
+	if event.kind == C.Y_UNDO_REDO {
+		kind = "redo"
+	}
+	return StackItem{
+		Kind:   kind,
+		Origin: C.GoBytes(unsafe.Pointer(event.origin), C.int(event.origin_len)),
+	}
+}
+
+// ObserveItemAdded registers a callback invoked every time a new entry is
+// pushed onto the undo or redo stack.
+func (m *UndoManager) ObserveItemAdded(cb func(StackItem)) *Subscription {
+	entry, ptr := registerHandle(cb)
+	sub := C.yrs_yundo_manager_observe_added(m.ptr, ptr)
+	return &Subscription{ptr: sub, entry: entry}
+}
+
+// ObserveItemPopped registers a callback invoked every time an entry is
+// popped off the undo or redo stack (i.e. an undo or redo was performed).
+func (m *UndoManager) ObserveItemPopped(cb func(StackItem)) *Subscription {
+	entry, ptr := registerHandle(cb)
+	sub := C.yrs_yundo_manager_observe_popped(m.ptr, ptr)
+	return &Subscription{ptr: sub, entry: entry}
+}
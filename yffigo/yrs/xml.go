@@ -0,0 +1,200 @@
+package yrs
+
+/*
+#include "./include/libyrs.h"
+#include <stdlib.h>
+*/
+import "C"
+import "unsafe"
+
+// GetYXmlFragment returns the root YXmlFragment branch by its name, creating
+// it the first time it is requested.
+func (d *YDoc) GetYXmlFragment(name string) *Branch {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	branch := C.yxmlfragment(d.ptr, cname)
+	return &Branch{ptr: branch}
+}
+
+// XmlFragmentInsertElement inserts a new YXmlElement with the given tag name
+// into this fragment (or element) at the given index, returning the newly
+// created branch.
+func (b *Branch) XmlFragmentInsertElement(txn *YTransaction, index uint32, tag string) *Branch {
+	ctag := C.CString(tag)
+	defer C.free(unsafe.Pointer(ctag))
+	elem := C.yxmlelem_insert_elem(b.ptr, txn.ptr, C.uint32_t(index), ctag)
+	return &Branch{ptr: elem}
+}
+
+// XmlFragmentInsertText inserts a new YXmlText at the given index into this
+// fragment (or element), returning the newly created branch.
+func (b *Branch) XmlFragmentInsertText(txn *YTransaction, index uint32) *Branch {
+	text := C.yxmlelem_insert_text(b.ptr, txn.ptr, C.uint32_t(index))
+	return &Branch{ptr: text}
+}
+
+// XmlElementTag returns the tag name of this YXmlElement.
+func (b *Branch) XmlElementTag() string {
+	ctag := C.yxmlelem_tag(b.ptr)
+	if ctag == nil {
+		return ""
+	}
+	defer C.ystring_destroy(ctag)
+	return C.GoString(ctag)
+}
+
+// XmlElementInsertAttribute sets an attribute on this YXmlElement.
+func (b *Branch) XmlElementInsertAttribute(txn *YTransaction, name, value string) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	cvalue := C.CString(value)
+	defer C.free(unsafe.Pointer(cvalue))
+	C.yxmlelem_insert_attr(b.ptr, txn.ptr, cname, cvalue)
+}
+
+// XmlElementRemoveAttribute removes an attribute from this YXmlElement.
+func (b *Branch) XmlElementRemoveAttribute(txn *YTransaction, name string) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	C.yxmlelem_remove_attr(b.ptr, txn.ptr, cname)
+}
+
+// XmlElementGetAttribute returns the value of an attribute on this
+// YXmlElement, or "" if it is not set.
+func (b *Branch) XmlElementGetAttribute(txn *YTransaction, name string) string {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	cvalue := C.yxmlelem_get_attr(b.ptr, txn.ptr, cname)
+	if cvalue == nil {
+		return ""
+	}
+	defer C.ystring_destroy(cvalue)
+	return C.GoString(cvalue)
+}
+
+// XmlAttr is a single name/value pair on a YXmlElement.
+type XmlAttr struct {
+	Name  string
+	Value string
+}
+
+// XmlElementAttrIter iterates over the attributes of a YXmlElement.
+type XmlElementAttrIter struct {
+	ptr *C.YXmlAttrIter
+}
+
+// XmlElementAttributes returns an iterator over this YXmlElement's
+// attributes.
+func (b *Branch) XmlElementAttributes(txn *YTransaction) *XmlElementAttrIter {
+	iter := C.yxmlelem_attr_iter(b.ptr, txn.ptr)
+	return &XmlElementAttrIter{ptr: iter}
+}
+
+// Next returns the next attribute, or nil once the iterator is exhausted.
+func (it *XmlElementAttrIter) Next() *XmlAttr {
+	entry := C.yxmlattr_iter_next(it.ptr)
+	if entry == nil {
+		return nil
+	}
+	defer C.yxmlattr_destroy(entry)
+	return &XmlAttr{Name: C.GoString(entry.name), Value: C.GoString(entry.value)}
+}
+
+// Destroy releases the resources associated with this attribute iterator.
+func (it *XmlElementAttrIter) Destroy() {
+	C.yxmlattr_iter_destroy(it.ptr)
+	it.ptr = nil
+}
+
+// XmlElementFirstChild returns the first child of this YXmlElement or
+// fragment, or nil if it has none.
+func (b *Branch) XmlElementFirstChild(txn *YTransaction) *Branch {
+	child := C.yxmlelem_first_child(b.ptr, txn.ptr)
+	if child == nil {
+		return nil
+	}
+	return &Branch{ptr: child}
+}
+
+// XmlElementNextSibling returns the next sibling of this YXmlElement or
+// YXmlText, or nil if it is the last child of its parent.
+func (b *Branch) XmlElementNextSibling(txn *YTransaction) *Branch {
+	sibling := C.yxmlelem_next_sibling(b.ptr, txn.ptr)
+	if sibling == nil {
+		return nil
+	}
+	return &Branch{ptr: sibling}
+}
+
+// XmlElementParent returns the parent of this YXmlElement or YXmlText, or
+// nil if it is the root fragment.
+func (b *Branch) XmlElementParent(txn *YTransaction) *Branch {
+	parent := C.yxmlelem_parent(b.ptr, txn.ptr)
+	if parent == nil {
+		return nil
+	}
+	return &Branch{ptr: parent}
+}
+
+// XmlTreeWalker walks the subtree rooted at a YXmlElement or fragment in
+// document order.
+type XmlTreeWalker struct {
+	ptr *C.YXmlTreeWalker
+}
+
+// XmlElementTreeWalker returns a depth-first iterator over the descendants
+// of this YXmlElement or fragment.
+func (b *Branch) XmlElementTreeWalker(txn *YTransaction) *XmlTreeWalker {
+	walker := C.yxmlelem_tree_walker(b.ptr, txn.ptr)
+	return &XmlTreeWalker{ptr: walker}
+}
+
+// Next returns the next node in the walk, or nil once the walk is
+// exhausted.
+func (w *XmlTreeWalker) Next() *Branch {
+	node := C.yxmltreewalker_next(w.ptr)
+	if node == nil {
+		return nil
+	}
+	return &Branch{ptr: node}
+}
+
+// Destroy releases the resources associated with this tree walker.
+func (w *XmlTreeWalker) Destroy() {
+	C.yxmltreewalker_destroy(w.ptr)
+	w.ptr = nil
+}
+
+// XmlTextString returns the string content of a YXmlText.
+func (b *Branch) XmlTextString(txn *YTransaction) string {
+	cstr := C.yxmltext_string(b.ptr, txn.ptr)
+	defer C.ystring_destroy(cstr)
+	return C.GoString(cstr)
+}
+
+// XmlTextInsert inserts text into a YXmlText at the given index, optionally
+// tagged with formatting attributes.
+func (b *Branch) XmlTextInsert(txn *YTransaction, index uint32, text string, attrs map[string]any) {
+	ctext := C.CString(text)
+	defer C.free(unsafe.Pointer(ctext))
+
+	if attrsInput := attrsToYInput(attrs); attrsInput != nil {
+		defer attrsInput.Free()
+		cAttrs := attrsInput.toC()
+		C.yxmltext_insert_with_attributes(b.ptr, txn.ptr, C.uint32_t(index), ctext, &cAttrs)
+		return
+	}
+	C.yxmltext_insert(b.ptr, txn.ptr, C.uint32_t(index), ctext)
+}
+
+// XmlTextFormat applies formatting attributes to a range of a YXmlText
+// without altering its content.
+func (b *Branch) XmlTextFormat(txn *YTransaction, index, length uint32, attrs map[string]any) {
+	attrsInput := attrsToYInput(attrs)
+	if attrsInput == nil {
+		return
+	}
+	defer attrsInput.Free()
+	cAttrs := attrsInput.toC()
+	C.yxmltext_format(b.ptr, txn.ptr, C.uint32_t(index), C.uint32_t(length), &cAttrs)
+}